@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jimmyfrasche/recent/recent"
+)
+
+//stringList implements flag.Value, collecting every occurrence of a
+//repeatable flag such as -exclude or -include into a slice.
+type stringList []string
+
+func (s *stringList) String() string {
+	return fmt.Sprint([]string(*s))
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+//timeFieldFlag implements flag.Value, collecting every occurrence of the
+//repeatable -time flag into a slice of recent.TimeFields.
+type timeFieldFlag []recent.TimeField
+
+func (l *timeFieldFlag) String() string {
+	return fmt.Sprint([]recent.TimeField(*l))
+}
+
+func (l *timeFieldFlag) Set(v string) error {
+	f, err := recent.ParseTimeField(v)
+	if err != nil {
+		return err
+	}
+	*l = append(*l, f)
+	return nil
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/jimmyfrasche/recent/recent"
+)
+
+//SortKey names a field matches can be sorted by.
+type SortKey string
+
+//The fields -sort accepts.
+const (
+	SortNone  SortKey = ""
+	SortMTime SortKey = "mtime"
+	SortName  SortKey = "name"
+	SortSize  SortKey = "size"
+)
+
+func parseSortKey(s string) (SortKey, error) {
+	switch SortKey(s) {
+	case SortMTime, SortName, SortSize:
+		return SortKey(s), nil
+	default:
+		return "", fmt.Errorf("recent: %q is not one of mtime, name, size", s)
+	}
+}
+
+//printer turns recent.Results into FileRecords and hands them to a Sink,
+//handling the trailing slash on directories and any sorting or limiting:
+//with neither Sort nor Limit set it emits each result as it arrives;
+//otherwise it buffers until Flush so the whole set can be sorted and
+//trimmed first.
+type printer struct {
+	Sort    SortKey
+	Reverse bool
+	Limit   int
+	NoSlash bool
+
+	Sink Sink
+
+	buf []recent.Result
+}
+
+//buffers reports whether results need to be held until Flush, rather than
+//emitted immediately as they're found: true if they need sorting,
+//reversing, or limiting.
+func (p *printer) buffers() bool {
+	return p.Sort != SortNone || p.Reverse || p.Limit != 0
+}
+
+func (p *printer) emit(r recent.Result) {
+	if !p.buffers() {
+		p.send(r)
+		return
+	}
+	p.buf = append(p.buf, r)
+}
+
+//Flush sorts and limits any results buffered by emit and sends them to
+//the Sink. It is a no-op if none of -sort, -reverse, or -n were requested.
+func (p *printer) Flush() {
+	if !p.buffers() {
+		return
+	}
+
+	results := p.buf
+	switch p.Sort {
+	case SortName:
+		sort.Slice(results, func(i, j int) bool { return results[i].Path < results[j].Path })
+	case SortSize:
+		sort.Slice(results, func(i, j int) bool { return size(results[i]) < size(results[j]) })
+	case SortMTime:
+		sort.Slice(results, func(i, j int) bool { return mtime(results[i]).Before(mtime(results[j])) })
+	}
+	if p.Reverse {
+		for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+			results[i], results[j] = results[j], results[i]
+		}
+	}
+	if p.Limit > 0 && p.Limit < len(results) {
+		results = results[:p.Limit]
+	}
+
+	for _, r := range results {
+		p.send(r)
+	}
+	p.buf = nil
+}
+
+//send converts r to a FileRecord and hands it to the Sink, stopping the
+//program on a write error the way any other fatal I/O failure would.
+func (p *printer) send(r recent.Result) {
+	if err := p.Sink.Emit(p.record(r)); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//record converts r to the FileRecord a Sink operates on, appending the
+//trailing / on directory names unless p.NoSlash is set.
+func (p *printer) record(r recent.Result) FileRecord {
+	if r.Info == nil {
+		return FileRecord{Path: r.Path, Removed: true}
+	}
+	path := r.Path
+	if r.Info.IsDir() && !p.NoSlash {
+		path += "/"
+	}
+	return FileRecord{
+		Path:  path,
+		MTime: r.Info.ModTime(),
+		Size:  r.Info.Size(),
+		Mode:  r.Info.Mode(),
+		IsDir: r.Info.IsDir(),
+	}
+}
+
+func mtime(r recent.Result) time.Time {
+	if r.Info == nil {
+		return time.Time{}
+	}
+	return r.Info.ModTime()
+}
+
+func size(r recent.Result) int64 {
+	if r.Info == nil {
+		return 0
+	}
+	return r.Info.Size()
+}
@@ -46,6 +46,60 @@
 //There is an implementation unit that the sum of the times
 //must be less than 290 years.
 //
+//Instead of a window relative to now, -after, -before, and -on
+//take an absolute time and accept RFC3339, "2006-01-02 15:04:05",
+//"2006-01-02", a unix timestamp (seconds, milliseconds, or microseconds,
+//detected from its length), or a duration string such as "36h", which is
+//taken as relative to now just like the time flags above.
+//Unlike the time flags, -after and -before may be used together to
+//bound both ends of the window, and -on is shorthand for the
+//24 hours of the given date.
+//Using any of -after, -before, or -on disables the implicit -d 1 default.
+//
+//By default all of the above compares against mtime, the last time a
+//file's content was modified. The -time flag, which may be repeated,
+//selects one or more of mtime, atime (last accessed), ctime (last
+//changed, content or metadata), and btime (created, where available)
+//instead; with more than one, a file matches if any of the selected
+//timestamps does. btime is not available on every platform or
+//filesystem, in which case it is simply never matched.
+//
+//Snapshots
+//
+//recent(1) can also report what changed relative to a prior snapshot of
+//a tree instead of a wall-clock window. -write-snapshot PATH records the
+//path, mtime, size, and mode of every currently matched file as a line
+//of JSON to PATH. -since-snapshot PATH compares the tree against such a
+//manifest and reports files whose mtime or size differ, plus any files
+//added or removed since it was taken. As elsewhere, -v inverts the
+//sense, here reporting unchanged files instead.
+//
+//Sorting and long format
+//
+//-sort {mtime,name,size} sorts matches instead of printing them as
+//they're found, and -reverse reverses that order (or, without -sort,
+//just reverses discovery order). -n N prints at most the first N
+//matches after any sorting.
+//
+//-l prints mtime, size, and path columns instead of just the path.
+//Its mtime column defaults to "2006-01-02 15:04:05" but -time-format
+//accepts "rfc3339", "relative" (producing output like "3h ago"), or any
+//Go time layout.
+//
+//Structured output
+//
+//-json prints one JSON object per match with path, mtime (RFC3339),
+//size, mode, and is_dir fields, for piping into jq or similar.
+//
+//-tsv prints the same mtime, size, and path columns as -l, but
+//tab-separated instead of aligned for reading.
+//
+//-format TEMPLATE renders each match with a Go text/template against a
+//FileRecord, whose exported fields are Path, MTime, Size, Mode, and
+//IsDir; for example -format '{{.Path}}: {{.Size}} bytes'.
+//
+//-json, -tsv, and -format are mutually exclusive with each other.
+//
 //Modifier flags
 //
 //To avoid appending a / to directory names, use the -no/ flag.
@@ -65,6 +119,25 @@
 //it separates files with null rather than a newline,
 //for use with xargs(1).
 //
+//Recursion
+//
+//By default recent(1) only considers the immediate contents of a directory.
+//The -r flag makes it descend into subdirectories without limit,
+//and -depth N limits that descent to N levels, implying -r.
+//
+//While recursing, recent(1) honors any .gitignore files it encounters,
+//skipping whatever they exclude, the same way git(1) would.
+//
+//The -exclude PATTERN and -include PATTERN flags, which may be repeated,
+//give further control: a path (or its base name) matching an -exclude
+//pattern is skipped, and, if any -include patterns are given, a path
+//must match one of them to be considered at all.
+//Patterns use the syntax of filepath.Match.
+//
+//Recursive directories are traversed by a pool of worker goroutines,
+//sized by -j N and defaulting to the number of CPUs, so large trees
+//aren't limited to reading one directory's entries at a time.
+//
 //Examples
 //
 //All examples assume the following files in the current directory:
@@ -109,17 +182,24 @@
 //	c/e
 //	c/.f
 //This invocation looks for all files that haven't been modified in the last two days.
+//
+//The matching engine behind recent(1) is also importable, as
+//github.com/jimmyfrasche/recent/recent, for programs that want the same
+//matching without shelling out.
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
-	"io"
 	"log"
 	"os"
-	"path/filepath"
+	"runtime"
 	"sort"
+	"text/template"
 	"time"
+
+	"github.com/jimmyfrasche/recent/recent"
 )
 
 //helper to sort and display flags
@@ -209,6 +289,18 @@ func allZero(xs ...*uint) bool {
 	return true
 }
 
+//boolCount returns how many of bs are true, for flags that are only
+//meaningful alone.
+func boolCount(bs ...bool) int {
+	n := 0
+	for _, b := range bs {
+		if b {
+			n++
+		}
+	}
+	return n
+}
+
 func toDuration(years, months, days, hours, mins *uint) time.Duration {
 	const (
 		day   = 24 * time.Hour
@@ -224,86 +316,18 @@ func toDuration(years, months, days, hours, mins *uint) time.Duration {
 	return d
 }
 
-//Matcher handles all the various matching scenarios.
-//Every nonbool needs to be set.
-type Matcher struct {
-	Invert      bool
-	IncludeDots bool
-	NoSlash     bool
-
-	Recent time.Duration
-	Now    time.Time
-
-	Out func(string)
-	Log func(error)
-}
-
-func (m *Matcher) dostat(skipdotcheck bool, prefix, name string, fi os.FileInfo) {
-	if !skipdotcheck && !m.IncludeDots && filepath.Base(name)[0] == '.' {
-		return
-	}
-
-	hit := m.Now.Sub(fi.ModTime()) < m.Recent
-	if m.Invert {
-		hit = !hit
-	}
-
-	if hit {
-		name = filepath.Clean(filepath.Join(prefix, name))
-		if fi.IsDir() && !m.NoSlash {
-			name += "/"
-		}
-		m.Out(name)
-	}
-}
-
-//Match a list of names from command line arguments.
-func (m *Matcher) Match(files []string) {
-	for _, file := range files {
-		fi, err := os.Stat(file)
-		if err != nil {
-			m.Log(err)
-			continue
-		}
-		//if we're explicitly given a directory, assume we are to read it.
-		if fi.IsDir() {
-			m.Readdir(file)
-		} else {
-			m.dostat(true, "", file, fi)
-		}
-	}
-}
-
-//Readdir matches the content of a directory
-func (m *Matcher) Readdir(dname string) {
-	d, err := os.Open(dname)
-	if err != nil {
-		m.Log(err)
-		return
-	}
-
-	for {
-		fis, err := d.Readdir(100)
-		for _, fi := range fis {
-			m.dostat(false, dname, fi.Name(), fi)
-		}
-		if err != nil {
-			if err != io.EOF {
-				m.Log(err)
-			}
-			return
-		}
-	}
-}
 
 func main() {
 	//Initial setup
 
-	matcher := Matcher{Now: time.Now()}
-
-	flag.BoolVar(&matcher.Invert, "v", false, "invert matches")
-	flag.BoolVar(&matcher.IncludeDots, ".", false, "include dot files")
-	flag.BoolVar(&matcher.NoSlash, "no/", false, "do not print / after directory names")
+	var (
+		invert      bool
+		includeDots bool
+		noSlash     bool
+	)
+	flag.BoolVar(&invert, "v", false, "invert matches")
+	flag.BoolVar(&includeDots, ".", false, "include dot files")
+	flag.BoolVar(&noSlash, "no/", false, "do not print / after directory names")
 
 	var (
 		noPrint = flag.Bool("q", false, "print nothing, exit with 1 if no files are recent")
@@ -314,8 +338,39 @@ func main() {
 		days   = flag.Uint("d", 0, "`days`")
 		months = flag.Uint("m", 0, "`months`")
 		years  = flag.Uint("y", 0, "`years`")
+
+		depth = flag.Int("depth", 0, "descend `N` levels into subdirectories, negative for unlimited; implies -r")
+
+		after  = flag.String("after", "", "only consider files modified after this `time`")
+		before = flag.String("before", "", "only consider files modified before this `time`")
+		on     = flag.String("on", "", "only consider files modified on this `date`")
+
+		writeSnapshotPath = flag.String("write-snapshot", "", "write a snapshot manifest of matched files to `path`, instead of listing them")
+		sinceSnapshotPath = flag.String("since-snapshot", "", "list files changed, added, or removed since the snapshot manifest at `path`")
+
+		sortKey    = flag.String("sort", "", "sort matches by `{mtime,name,size}`")
+		timeFormat = flag.String("time-format", "", "`layout` for -l's mtime column: a Go time layout, or iso, rfc3339, relative")
+		limit      = flag.Int("n", 0, "print at most `N` matches")
+
+		reverse = flag.Bool("reverse", false, "reverse the sort order, or the discovery order if -sort isn't given")
+		long    = flag.Bool("l", false, "long format: mtime, size, and path columns")
+
+		recursive = flag.Bool("r", false, "descend into subdirectories without limit")
+
+		jobs = flag.Int("j", runtime.NumCPU(), "traverse directories using `N` worker goroutines")
+
+		jsonOut    = flag.Bool("json", false, "print one JSON object per match instead of a path")
+		tsvOut     = flag.Bool("tsv", false, "print matches as tab-separated mtime, size, and path columns")
+		formatTmpl = flag.String("format", "", "render each match with this text/template `template` against a FileRecord")
 	)
 
+	var exclude, include stringList
+	flag.Var(&exclude, "exclude", "skip paths matching `pattern` (may be repeated)")
+	flag.Var(&include, "include", "only consider paths matching `pattern` (may be repeated)")
+
+	var timeFields timeFieldFlag
+	flag.Var(&timeFields, "time", "compare `{mtime,atime,ctime,btime}` against the window (may be repeated, OR'd together)")
+
 	flag.Parse()
 
 	//validate additional constraints
@@ -323,45 +378,172 @@ func main() {
 	if *print0 && *noPrint {
 		log.Fatal("-print0 and -q are fundamentally opposed ideas.")
 	}
+	if n := boolCount(*jsonOut, *tsvOut, *formatTmpl != ""); n > 1 {
+		log.Fatal("-json, -tsv, and -format are mutually exclusive.")
+	}
+
+	now := time.Now()
+
+	maxDepth := *depth
+	if *depth == 0 && *recursive {
+		maxDepth = -1
+	}
 
-	//default to one day
-	if allZero(years, months, days, hours, mins) {
+	haveAbsolute := *after != "" || *before != "" || *on != ""
+
+	//default to one day, unless an absolute time flag or a snapshot takes over that duty
+	if allZero(years, months, days, hours, mins) && !haveAbsolute && *writeSnapshotPath == "" {
 		*days = 1
 	}
 
 	//Configure
 
-	matcher.Recent = toDuration(years, months, days, hours, mins)
-
-	matcher.Log = func(e error) {
-		log.Println(e)
+	opts := []recent.Option{
+		recent.WithNow(now),
+		recent.WithIncludeDots(includeDots),
+		recent.WithRecursion(maxDepth),
+		recent.WithExclude(exclude...),
+		recent.WithInclude(include...),
+		recent.WithTimeField(timeFields...),
+		recent.WithWorkers(*jobs),
 	}
 
-	matched := false //only used if *noPrint
-	if *noPrint {
-		matcher.Out = func(string) {
-			matched = true
+	if sum := toDuration(years, months, days, hours, mins); sum > 0 {
+		opts = append(opts, recent.WithSince(now.Add(-sum)))
+	}
+	if *after != "" {
+		t, _, err := recent.ParseTime(*after, now)
+		if err != nil {
+			log.Fatal(err)
 		}
-	} else if *print0 {
-		matcher.Out = func(s string) {
-			fmt.Printf("%s\000", s)
+		opts = append(opts, recent.WithSince(t))
+	}
+	if *before != "" {
+		t, _, err := recent.ParseTime(*before, now)
+		if err != nil {
+			log.Fatal(err)
 		}
-	} else {
-		matcher.Out = func(s string) {
-			fmt.Println(s)
+		opts = append(opts, recent.WithUntil(t))
+	}
+	if *on != "" {
+		t, dateOnly, err := recent.ParseTime(*on, now)
+		if err != nil {
+			log.Fatal(err)
+		}
+		until := t
+		if dateOnly {
+			until = t.Add(24 * time.Hour)
 		}
+		opts = append(opts, recent.WithSince(t), recent.WithUntil(until))
 	}
 
-	//Run
+	if *sinceSnapshotPath != "" {
+		snap, err := recent.LoadSnapshot(*sinceSnapshotPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts = append(opts, recent.WithSnapshot(snap))
+	}
 
+	var paths []string
 	if flag.NArg() > 0 {
-		matcher.Match(flag.Args())
+		paths = flag.Args()
 	} else {
-		matcher.Readdir(".")
+		paths = []string{"."}
+	}
+
+	if *writeSnapshotPath != "" {
+		writeSnapshot(*writeSnapshotPath, paths, opts)
+		return
+	}
+
+	//a snapshot comparison is always reported against -v; anywhere else
+	//-v simply inverts which files match
+	opts = append(opts, recent.WithInvert(invert))
+
+	quiet := &quietSink{} //only used if *noPrint
+	var sink Sink
+	switch {
+	case *noPrint:
+		sink = quiet
+	case *jsonOut:
+		sink = newJSONSink(os.Stdout)
+	case *tsvOut:
+		sink = &tsvSink{w: os.Stdout, TimeFormat: *timeFormat, Now: now}
+	case *formatTmpl != "":
+		tmpl, err := template.New("format").Parse(*formatTmpl)
+		if err != nil {
+			log.Fatal(err)
+		}
+		sink = &formatSink{tmpl: tmpl, w: os.Stdout}
+	case *print0:
+		sink = &plainSink{w: os.Stdout, sep: "\000", Long: *long, TimeFormat: *timeFormat, Now: now}
+	default:
+		sink = &plainSink{w: os.Stdout, sep: "\n", Long: *long, TimeFormat: *timeFormat, Now: now}
+	}
+
+	p := &printer{
+		Sort:    SortNone,
+		Reverse: *reverse,
+		Limit:   *limit,
+		NoSlash: noSlash,
+		Sink:    sink,
+	}
+	if *sortKey != "" {
+		k, err := parseSortKey(*sortKey)
+		if err != nil {
+			log.Fatal(err)
+		}
+		p.Sort = k
+	}
+
+	//Run
+
+	ch, err := recent.Match(context.Background(), paths, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+	for r := range ch {
+		if r.Err != nil {
+			log.Println(r.Err)
+			continue
+		}
+		p.emit(r)
+	}
+	p.Flush()
+	if err := sink.Close(); err != nil {
+		log.Fatal(err)
 	}
 
 	//if there were no matches and we were told not to print, use exit code
-	if *noPrint && !matched {
+	if *noPrint && !quiet.Matched {
 		os.Exit(1)
 	}
 }
+
+//writeSnapshot walks paths under opts and records every matched file's
+//path, mtime, size, and mode as a snapshot manifest at path.
+func writeSnapshot(path string, paths []string, opts []recent.Option) {
+	ch, err := recent.Match(context.Background(), paths, opts...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var recs []recent.Record
+	for r := range ch {
+		if r.Err != nil {
+			log.Println(r.Err)
+			continue
+		}
+		recs = append(recs, recent.Record{
+			Path:  r.Path,
+			MTime: r.Info.ModTime(),
+			Size:  r.Info.Size(),
+			Mode:  r.Info.Mode(),
+		})
+	}
+
+	if err := recent.WriteSnapshot(path, recs); err != nil {
+		log.Fatal(err)
+	}
+}
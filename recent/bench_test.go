@@ -0,0 +1,57 @@
+package recent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+)
+
+//buildBenchTree writes a synthetic tree of dirs*filesPerDir files under a
+//fresh temp directory, for comparing the serial and parallel walkers.
+func buildBenchTree(b *testing.B, dirs, filesPerDir int) string {
+	b.Helper()
+	root := b.TempDir()
+	for i := 0; i < dirs; i++ {
+		dir := filepath.Join(root, fmt.Sprintf("d%d", i))
+		if err := os.Mkdir(dir, 0o755); err != nil {
+			b.Fatal(err)
+		}
+		for j := 0; j < filesPerDir; j++ {
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.txt", j)), nil, 0o644); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+	return root
+}
+
+//runBenchMatch recursively matches everything under root with the given
+//worker count, draining the channel so the walk actually completes.
+func runBenchMatch(b *testing.B, root string, workers int) {
+	b.Helper()
+	for i := 0; i < b.N; i++ {
+		ch, err := Match(context.Background(), []string{root},
+			WithRecursion(-1), WithWorkers(workers), WithSince(time.Time{}))
+		if err != nil {
+			b.Fatal(err)
+		}
+		for range ch {
+		}
+	}
+}
+
+func BenchmarkMatchSerial(b *testing.B) {
+	root := buildBenchTree(b, 50, 20)
+	b.ResetTimer()
+	runBenchMatch(b, root, 1)
+}
+
+func BenchmarkMatchParallel(b *testing.B) {
+	root := buildBenchTree(b, 50, 20)
+	b.ResetTimer()
+	runBenchMatch(b, root, runtime.NumCPU())
+}
@@ -0,0 +1,129 @@
+package recent
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//ignoreRule is a single parsed, non-blank, non-comment line from a .gitignore file.
+type ignoreRule struct {
+	negate   bool     //line began with !
+	dirOnly  bool     //line ended in / and only matches directories
+	anchored bool     //pattern contains a / other than a trailing one, so it's relative to dir
+	segs     []string //pattern split on /, with any trailing slash removed
+}
+
+//ignoreFile is the parsed rules from a single .gitignore, along with the
+//directory it was found in so matches can be made relative to it.
+type ignoreFile struct {
+	dir   string
+	rules []ignoreRule
+}
+
+//loadIgnoreFile parses dir/.gitignore, if present. A nil *ignoreFile with a
+//nil error means there was no .gitignore to load.
+func loadIgnoreFile(dir string) (*ignoreFile, error) {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	ig := &ignoreFile{dir: dir}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		rule.anchored = strings.Contains(strings.TrimPrefix(line, "/"), "/")
+		line = strings.TrimPrefix(line, "/")
+		rule.segs = strings.Split(line, "/")
+
+		ig.rules = append(ig.rules, rule)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return ig, nil
+}
+
+//ignored reports whether path (clean, slash-separated, relative to the
+//current working directory) is excluded by any of the accumulated
+//.gitignore files in ignores, which must be ordered outermost first.
+//As in git, later rules override earlier ones and a negated rule can
+//re-include a path matched by an earlier rule.
+func ignored(ignores []*ignoreFile, path string, isDir bool) bool {
+	ig := false
+	for _, f := range ignores {
+		rel, err := filepath.Rel(f.dir, path)
+		if err != nil {
+			continue
+		}
+		rel = filepath.ToSlash(rel)
+		for _, r := range f.rules {
+			if r.matches(rel, isDir) {
+				ig = !r.negate
+			}
+		}
+	}
+	return ig
+}
+
+func (r ignoreRule) matches(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	segs := strings.Split(rel, "/")
+	if r.anchored {
+		return matchSegs(r.segs, segs)
+	}
+	for i := range segs {
+		if matchSegs(r.segs, segs[i:]) {
+			return true
+		}
+	}
+	return false
+}
+
+//matchSegs matches path segments against gitignore pattern segments,
+//where a "**" segment matches any number, including zero, of path segments.
+func matchSegs(pat, name []string) bool {
+	if len(pat) == 0 {
+		return len(name) == 0
+	}
+	if pat[0] == "**" {
+		if len(pat) == 1 {
+			return true
+		}
+		for i := 0; i <= len(name); i++ {
+			if matchSegs(pat[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	ok, err := filepath.Match(pat[0], name[0])
+	if err != nil || !ok {
+		return false
+	}
+	return matchSegs(pat[1:], name[1:])
+}
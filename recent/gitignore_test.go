@@ -0,0 +1,83 @@
+package recent
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMatchSegs(t *testing.T) {
+	cases := []struct {
+		pat, name string
+		want      bool
+	}{
+		{"a/b", "a/b", true},
+		{"a/b", "a/c", false},
+		{"*.go", "main.go", true},
+		{"*.go", "main.js", false},
+		{"**/b", "a/x/y/b", true},
+		{"**/b", "b", true},
+		{"a/**", "a/x/y", true},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+	}
+	for _, c := range cases {
+		got := matchSegs(strings.Split(c.pat, "/"), strings.Split(c.name, "/"))
+		if got != c.want {
+			t.Errorf("matchSegs(%q, %q) = %v, want %v", c.pat, c.name, got, c.want)
+		}
+	}
+}
+
+func TestIgnored(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, contents string) {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(contents), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".gitignore", "*.log\nbuild/\n!important.log\n")
+
+	ig, err := loadIgnoreFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ig == nil {
+		t.Fatal("loadIgnoreFile returned nil for a directory with a .gitignore")
+	}
+
+	cases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{filepath.Join(dir, "debug.log"), false, true},
+		{filepath.Join(dir, "important.log"), false, false},
+		{filepath.Join(dir, "main.go"), false, false},
+		{filepath.Join(dir, "build"), true, true},
+		{filepath.Join(dir, "build"), false, false}, //dirOnly rule, not a dir here
+	}
+	for _, c := range cases {
+		got := ignored([]*ignoreFile{ig}, c.path, c.isDir)
+		if got != c.want {
+			t.Errorf("ignored(%q, isDir=%v) = %v, want %v", c.path, c.isDir, got, c.want)
+		}
+	}
+}
+
+func TestLoadIgnoreFileMissing(t *testing.T) {
+	ig, err := loadIgnoreFile(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ig != nil {
+		t.Fatal("loadIgnoreFile should return a nil *ignoreFile when there is no .gitignore")
+	}
+}
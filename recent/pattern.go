@@ -0,0 +1,20 @@
+package recent
+
+import (
+	"path/filepath"
+)
+
+//matchAny reports whether any pattern in pats matches name using
+//filepath.Match semantics, trying both the full (slash-separated) path
+//and its base name so a pattern like "*.go" matches regardless of depth.
+func matchAny(pats []string, path, base string) bool {
+	for _, p := range pats {
+		if ok, err := filepath.Match(p, base); err == nil && ok {
+			return true
+		}
+		if ok, err := filepath.Match(p, path); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package recent
+
+import "sync"
+
+//dirTask is one directory awaiting traversal by a worker.
+type dirTask struct {
+	path    string
+	depth   int
+	ignores []*ignoreFile
+}
+
+//dirQueue is an unbounded FIFO of dirTasks shared by a pool of workers.
+//pending counts tasks that have been pushed but not yet marked done,
+//including any still sitting in items; pop returns ok=false once it
+//reaches zero, telling every worker there is no more work coming.
+type dirQueue struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	items   []dirTask
+	pending int
+}
+
+func newDirQueue() *dirQueue {
+	q := &dirQueue{}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+//push adds t to the queue. Call before the task that discovered it calls
+//done, so pending never drops to zero while t is still in flight.
+func (q *dirQueue) push(t dirTask) {
+	q.mu.Lock()
+	q.items = append(q.items, t)
+	q.pending++
+	q.cond.Signal()
+	q.mu.Unlock()
+}
+
+//pop removes and returns the next task, blocking until one is available.
+//It returns ok=false once pending reaches zero, meaning the walk is done.
+func (q *dirQueue) pop() (t dirTask, ok bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for len(q.items) == 0 {
+		if q.pending == 0 {
+			return dirTask{}, false
+		}
+		q.cond.Wait()
+	}
+	t, q.items = q.items[0], q.items[1:]
+	return t, true
+}
+
+//done marks a task popped earlier as fully processed, including having
+//pushed any subdirectories it found.
+func (q *dirQueue) done() {
+	q.mu.Lock()
+	q.pending--
+	if q.pending == 0 {
+		q.cond.Broadcast()
+	}
+	q.mu.Unlock()
+}
@@ -0,0 +1,359 @@
+//Package recent finds files by when they were last modified, accessed,
+//changed, or created, optionally against a prior snapshot of the tree.
+//It is the matching engine behind the recent(1) command, usable on its
+//own by other Go programs.
+package recent
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+//Result is a single match, or an error encountered while producing one.
+//Err is non-nil exactly when Info is nil.
+type Result struct {
+	Path string
+	Info os.FileInfo
+	Err  error
+}
+
+//config accumulates the effect of a set of Options.
+type config struct {
+	invert      bool
+	includeDots bool
+
+	maxDepth int //0: don't descend into subdirectories; negative: unlimited
+
+	exclude []string
+	include []string
+
+	timeFields []TimeField
+	timeRange  TimeRange
+
+	snapshot *Snapshot
+
+	now     time.Time
+	workers int
+}
+
+//Option configures a call to Match.
+type Option func(*config)
+
+//WithNow overrides the time Match treats as the present, which a zero
+//WithSince/WithUntil/WithRecursion/etc. call never needs but relative
+//and duration-based callers may want fixed for reproducibility.
+func WithNow(t time.Time) Option {
+	return func(c *config) { c.now = t }
+}
+
+//WithSince only matches timestamps at or after t.
+func WithSince(t time.Time) Option {
+	return func(c *config) { c.timeRange.Since = t }
+}
+
+//WithUntil only matches timestamps at or before t.
+func WithUntil(t time.Time) Option {
+	return func(c *config) { c.timeRange.Until = t }
+}
+
+//WithInvert reports files that do not match instead of those that do.
+func WithInvert(invert bool) Option {
+	return func(c *config) { c.invert = invert }
+}
+
+//WithIncludeDots considers dot files and directories, which are skipped
+//by default.
+func WithIncludeDots(include bool) Option {
+	return func(c *config) { c.includeDots = include }
+}
+
+//WithRecursion descends into subdirectories up to maxDepth levels;
+//negative means unlimited, and 0, the default, means not at all.
+//While descending, any .gitignore files encountered are honored.
+func WithRecursion(maxDepth int) Option {
+	return func(c *config) { c.maxDepth = maxDepth }
+}
+
+//WithTimeField compares the given timestamps, OR'd together, instead of
+//the default of just mtime.
+func WithTimeField(fields ...TimeField) Option {
+	return func(c *config) { c.timeFields = append(c.timeFields, fields...) }
+}
+
+//WithExclude skips paths matching any of the given filepath.Match patterns.
+func WithExclude(patterns ...string) Option {
+	return func(c *config) { c.exclude = append(c.exclude, patterns...) }
+}
+
+//WithInclude, if given, requires a path to match one of the given
+//filepath.Match patterns to be considered at all.
+func WithInclude(patterns ...string) Option {
+	return func(c *config) { c.include = append(c.include, patterns...) }
+}
+
+//WithSnapshot matches against a prior snapshot instead of a time window:
+//a Result is produced for every file that was added, removed, or whose
+//mtime or size differs, or, with WithInvert, for every unchanged file.
+func WithSnapshot(s *Snapshot) Option {
+	return func(c *config) { c.snapshot = s }
+}
+
+//WithWorkers sets how many goroutines traverse directories concurrently
+//during recursion; n<=1 walks one directory at a time. Match defaults to
+//1 if this is never given.
+func WithWorkers(n int) Option {
+	return func(c *config) { c.workers = n }
+}
+
+//Match walks paths, sending a Result for each file that matches
+//according to opts. The channel is closed once the walk finishes or ctx
+//is done. Errors encountered while walking (such as an unreadable
+//directory) are sent as Results with Err set rather than stopping the walk.
+func Match(ctx context.Context, paths []string, opts ...Option) (<-chan Result, error) {
+	if len(paths) == 0 {
+		return nil, errors.New("recent: no paths given")
+	}
+
+	c := &config{now: time.Now()}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.workers <= 0 {
+		c.workers = 1
+	}
+
+	ch := make(chan Result)
+	w := &walker{config: c, ctx: ctx, ch: ch, queue: newDirQueue()}
+
+	go func() {
+		defer close(ch)
+		for _, p := range paths {
+			if ctx.Err() != nil {
+				return
+			}
+			w.matchPath(p)
+		}
+		w.runWorkers()
+		if c.snapshot != nil && !c.invert {
+			for _, p := range c.snapshot.Removed() {
+				if !w.send(Result{Path: p}) {
+					return
+				}
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+//walker carries the state of a single Match call as it walks the
+//filesystem and sends Results. Directories discovered while recursing are
+//pushed onto queue and picked up by whichever worker is free next, so the
+//walk fans out across w.workers goroutines instead of descending one
+//directory at a time.
+type walker struct {
+	*config
+	ctx   context.Context
+	ch    chan<- Result
+	queue *dirQueue
+}
+
+//runWorkers starts w.workers goroutines draining w.queue and blocks until
+//every pushed directory, including ones discovered along the way, has
+//been processed.
+func (w *walker) runWorkers() {
+	var wg sync.WaitGroup
+	wg.Add(w.workers)
+	for i := 0; i < w.workers; i++ {
+		go func() {
+			defer wg.Done()
+			for {
+				t, ok := w.queue.pop()
+				if !ok {
+					return
+				}
+				if w.ctx.Err() == nil {
+					w.processDir(t)
+				}
+				w.queue.done()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+//send delivers r, reporting whether the caller should keep going: it
+//returns false once ctx is done, at which point the caller should
+//unwind without sending anything further.
+func (w *walker) send(r Result) bool {
+	select {
+	case w.ch <- r:
+		return true
+	case <-w.ctx.Done():
+		return false
+	}
+}
+
+//matchPath matches a single command-line-style argument: a directory is
+//read as if Match had been pointed at it, anything else is stat'd directly.
+func (w *walker) matchPath(path string) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		w.send(Result{Path: path, Err: err})
+		return
+	}
+	if fi.IsDir() {
+		w.queue.push(dirTask{path: path, depth: 0})
+	} else {
+		w.dostat(true, "", path, fi)
+	}
+}
+
+//processDir reads one directory, sends its matches, and pushes any
+//subdirectory worth descending into back onto w.queue for some worker,
+//possibly this one, to pick up.
+func (w *walker) processDir(t dirTask) {
+	dname, depth, ignores := t.path, t.depth, t.ignores
+	if w.maxDepth != 0 {
+		ig, err := loadIgnoreFile(dname)
+		if err != nil {
+			w.send(Result{Path: dname, Err: err})
+		} else if ig != nil {
+			//ignores is shared with sibling tasks that were pushed
+			//alongside this one, so appending through it as-is could
+			//write into the same backing array two goroutines at
+			//once; the three-index slice forces a fresh allocation.
+			ignores = append(ignores[:len(ignores):len(ignores)], ig)
+		}
+	}
+
+	entries, err := os.ReadDir(dname)
+	if err != nil {
+		w.send(Result{Path: dname, Err: err})
+		return
+	}
+
+	for _, entry := range entries {
+		if w.ctx.Err() != nil {
+			return
+		}
+
+		name := entry.Name()
+		path := filepath.Clean(filepath.Join(dname, name))
+		isDir := entry.IsDir()
+		if ignored(ignores, path, isDir) {
+			continue
+		}
+
+		fi, err := entry.Info()
+		if err != nil {
+			w.send(Result{Path: path, Err: err})
+			continue
+		}
+
+		if !w.dostat(false, dname, name, fi) {
+			return
+		}
+
+		if isDir &&
+			(w.includeDots || name[0] != '.') &&
+			(w.maxDepth < 0 || depth < w.maxDepth) &&
+			!matchAny(w.exclude, path, filepath.Base(path)) {
+			w.queue.push(dirTask{path: path, depth: depth + 1, ignores: ignores})
+		}
+	}
+}
+
+//excluded reports whether path should be skipped because of -exclude or
+//-include: a path matching -exclude, or failing every -include pattern
+//when any are given, is excluded. -include only filters which matches
+//are reported, so it has no bearing on whether processDir descends into
+//a directory; -exclude prunes the subtree outright, the same as a
+//.gitignore rule would, since it's never useful to recurse into
+//something explicitly excluded.
+func (w *walker) excluded(path string) bool {
+	if len(w.include) > 0 && !matchAny(w.include, path, filepath.Base(path)) {
+		return true
+	}
+	return matchAny(w.exclude, path, filepath.Base(path))
+}
+
+//dostat decides whether fi is a match and, if so, sends it, reporting
+//whether the walk should continue.
+func (w *walker) dostat(skipdotcheck bool, prefix, name string, fi os.FileInfo) bool {
+	if !skipdotcheck && !w.includeDots && filepath.Base(name)[0] == '.' {
+		return true
+	}
+
+	path := filepath.Clean(filepath.Join(prefix, name))
+	if w.excluded(path) {
+		return true
+	}
+
+	var hit bool
+	if w.snapshot != nil {
+		hit = w.snapshot.changed(path, fi)
+	} else {
+		for _, t := range w.timestamps(fi) {
+			if w.timeRange.Contains(t) {
+				hit = true
+				break
+			}
+		}
+	}
+	if w.invert {
+		hit = !hit
+	}
+
+	if !hit {
+		return true
+	}
+	return w.send(Result{Path: path, Info: fi})
+}
+
+//timestamps returns the timestamps of fi selected by w.timeFields,
+//defaulting to just ModTime. Extra timestamps are only read from the
+//platform, via platformTimes, when atime, ctime, or btime is requested.
+func (w *walker) timestamps(fi os.FileInfo) []time.Time {
+	if len(w.timeFields) == 0 {
+		return []time.Time{fi.ModTime()}
+	}
+
+	var atime, ctime, btime time.Time
+	var ctimeOK, btimeOK, read bool
+
+	ts := make([]time.Time, 0, len(w.timeFields))
+	for _, f := range w.timeFields {
+		switch f {
+		case MTime:
+			ts = append(ts, fi.ModTime())
+		case ATime:
+			if !read {
+				atime, ctime, ctimeOK, btime, btimeOK = platformTimes(fi)
+				read = true
+			}
+			ts = append(ts, atime)
+		case CTime:
+			if !read {
+				atime, ctime, ctimeOK, btime, btimeOK = platformTimes(fi)
+				read = true
+			}
+			if ctimeOK {
+				ts = append(ts, ctime)
+			}
+		case BTime:
+			if !read {
+				atime, ctime, ctimeOK, btime, btimeOK = platformTimes(fi)
+				read = true
+			}
+			if btimeOK {
+				ts = append(ts, btime)
+			}
+		}
+	}
+	return ts
+}
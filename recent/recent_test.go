@@ -0,0 +1,145 @@
+package recent
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+//buildTree creates, under a fresh temp directory, the following layout
+//and returns its root:
+//
+//	root.txt
+//	a/a.txt
+//	a/b/b.txt
+//	a/b/c/c.txt
+func buildTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+
+	write := func(rel string) {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("root.txt")
+	write("a/a.txt")
+	write("a/b/b.txt")
+	write("a/b/c/c.txt")
+
+	return root
+}
+
+//collect drains Match's result channel and returns the matched paths,
+//relative to root, in sorted order. It fails the test on any Result.Err.
+func collect(t *testing.T, root string, opts ...Option) []string {
+	t.Helper()
+	ch, err := Match(context.Background(), []string{root}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for r := range ch {
+		if r.Err != nil {
+			t.Fatalf("unexpected error for %s: %v", r.Path, r.Err)
+		}
+		rel, err := filepath.Rel(root, r.Path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, filepath.ToSlash(rel))
+	}
+	sort.Strings(got)
+	return got
+}
+
+func TestMatchRecursionDepth(t *testing.T) {
+	root := buildTree(t)
+	since := time.Now().Add(-time.Hour)
+
+	cases := []struct {
+		depth int
+		want  []string
+	}{
+		{0, []string{"a", "root.txt"}},
+		{1, []string{"a", "a/a.txt", "a/b", "root.txt"}},
+		{2, []string{"a", "a/a.txt", "a/b", "a/b/b.txt", "a/b/c", "root.txt"}},
+		{3, []string{"a", "a/a.txt", "a/b", "a/b/b.txt", "a/b/c", "a/b/c/c.txt", "root.txt"}},
+		{-1, []string{"a", "a/a.txt", "a/b", "a/b/b.txt", "a/b/c", "a/b/c/c.txt", "root.txt"}},
+	}
+	for _, c := range cases {
+		got := collect(t, root, WithRecursion(c.depth), WithSince(since))
+		if !equalStrings(got, c.want) {
+			t.Errorf("depth %d: got %v, want %v", c.depth, got, c.want)
+		}
+	}
+}
+
+func TestMatchExcludePrunesSubtree(t *testing.T) {
+	root := buildTree(t)
+	since := time.Now().Add(-time.Hour)
+
+	got := collect(t, root, WithRecursion(-1), WithSince(since), WithExclude("b"))
+	want := []string{"a", "a/a.txt", "root.txt"}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMatchIncludeDoesNotPruneSubtree(t *testing.T) {
+	root := buildTree(t)
+	since := time.Now().Add(-time.Hour)
+
+	got := collect(t, root, WithRecursion(-1), WithSince(since), WithInclude("c.txt"))
+	want := []string{"a/b/c/c.txt"}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestMatchWorkerPoolMatchesSerial(t *testing.T) {
+	root := buildTree(t)
+	since := time.Now().Add(-time.Hour)
+
+	serial := collect(t, root, WithRecursion(-1), WithSince(since))
+	parallel := collect(t, root, WithRecursion(-1), WithSince(since), WithWorkers(4))
+	if !equalStrings(serial, parallel) {
+		t.Errorf("parallel walk = %v, want %v", parallel, serial)
+	}
+}
+
+func TestMatchSinceUntil(t *testing.T) {
+	root := buildTree(t)
+	now := time.Now()
+
+	got := collect(t, root, WithRecursion(-1), WithUntil(now.Add(-time.Hour)))
+	if len(got) != 0 {
+		t.Errorf("WithUntil in the past should match nothing, got %v", got)
+	}
+
+	got = collect(t, root, WithRecursion(-1), WithSince(now.Add(-time.Hour)))
+	want := []string{"a", "a/a.txt", "a/b", "a/b/b.txt", "a/b/c", "a/b/c/c.txt", "root.txt"}
+	if !equalStrings(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
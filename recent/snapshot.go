@@ -0,0 +1,106 @@
+package recent
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+//Record is one line of a snapshot manifest, describing a single file as
+//it stood when the manifest was written.
+type Record struct {
+	Path  string      `json:"path"`
+	MTime time.Time   `json:"mtime"`
+	Size  int64       `json:"size"`
+	Mode  os.FileMode `json:"mode"`
+}
+
+//Snapshot is a previously written manifest, used to find files that have
+//been added, removed, or changed since it was taken. changed is called
+//concurrently by the worker pool during a recursive walk, so seen is
+//guarded by mu; prior is only ever read after LoadSnapshot returns.
+type Snapshot struct {
+	prior map[string]Record
+
+	mu   sync.Mutex
+	seen map[string]bool
+}
+
+//LoadSnapshot reads a manifest of JSON lines, as written by WriteSnapshot,
+//from path.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	s := &Snapshot{
+		prior: map[string]Record{},
+		seen:  map[string]bool{},
+	}
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var rec Record
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, err
+		}
+		s.prior[rec.Path] = rec
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+//changed reports whether path's current state differs from the snapshot,
+//recording path as seen so Removed can later report what's left over.
+//A path absent from the snapshot counts as changed.
+func (s *Snapshot) changed(path string, fi os.FileInfo) bool {
+	s.mu.Lock()
+	s.seen[path] = true
+	s.mu.Unlock()
+
+	prev, ok := s.prior[path]
+	if !ok {
+		return true
+	}
+	return !prev.MTime.Equal(fi.ModTime()) || prev.Size != fi.Size()
+}
+
+//Removed returns, in sorted order, the paths present in the snapshot that
+//were never passed to changed, i.e. that no longer exist.
+func (s *Snapshot) Removed() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var removed []string
+	for path := range s.prior {
+		if !s.seen[path] {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	return removed
+}
+
+//WriteSnapshot writes recs as a manifest of JSON lines to path.
+func WriteSnapshot(path string, recs []Record) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	enc := json.NewEncoder(f)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
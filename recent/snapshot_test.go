@@ -0,0 +1,91 @@
+package recent
+
+import (
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+type fakeFileInfo struct {
+	name  string
+	size  int64
+	mtime time.Time
+}
+
+func (f fakeFileInfo) Name() string       { return f.name }
+func (f fakeFileInfo) Size() int64        { return f.size }
+func (f fakeFileInfo) Mode() os.FileMode  { return 0o644 }
+func (f fakeFileInfo) ModTime() time.Time { return f.mtime }
+func (f fakeFileInfo) IsDir() bool        { return false }
+func (f fakeFileInfo) Sys() interface{}   { return nil }
+
+func TestSnapshotChanged(t *testing.T) {
+	mtime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &Snapshot{
+		prior: map[string]Record{
+			"same.txt":      {Path: "same.txt", MTime: mtime, Size: 10},
+			"resized.txt":   {Path: "resized.txt", MTime: mtime, Size: 10},
+			"retouched.txt": {Path: "retouched.txt", MTime: mtime, Size: 10},
+		},
+		seen: map[string]bool{},
+	}
+
+	if s.changed("new.txt", fakeFileInfo{mtime: mtime, size: 1}) != true {
+		t.Error("a path absent from the snapshot should count as changed")
+	}
+	if s.changed("same.txt", fakeFileInfo{mtime: mtime, size: 10}) != false {
+		t.Error("identical mtime and size should not count as changed")
+	}
+	if s.changed("resized.txt", fakeFileInfo{mtime: mtime, size: 20}) != true {
+		t.Error("a different size should count as changed")
+	}
+	if s.changed("retouched.txt", fakeFileInfo{mtime: mtime.Add(time.Hour), size: 10}) != true {
+		t.Error("a different mtime should count as changed")
+	}
+
+	removed := s.Removed()
+	if len(removed) != 0 {
+		t.Errorf("every prior path was passed to changed, so none should be reported removed, got %v", removed)
+	}
+}
+
+func TestSnapshotRemoved(t *testing.T) {
+	s := &Snapshot{
+		prior: map[string]Record{
+			"kept.txt":    {Path: "kept.txt"},
+			"deleted.txt": {Path: "deleted.txt"},
+		},
+		seen: map[string]bool{},
+	}
+	s.changed("kept.txt", fakeFileInfo{})
+
+	removed := s.Removed()
+	if len(removed) != 1 || removed[0] != "deleted.txt" {
+		t.Errorf("Removed() = %v, want [deleted.txt]", removed)
+	}
+}
+
+//TestSnapshotChangedConcurrent exercises changed the way the parallel
+//directory walker does: many goroutines racing to record paths in the
+//same Snapshot. Run with -race; it previously crashed with "fatal error:
+//concurrent map writes" before Snapshot gained a mutex.
+func TestSnapshotChangedConcurrent(t *testing.T) {
+	s := &Snapshot{
+		prior: map[string]Record{},
+		seen:  map[string]bool{},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for j := 0; j < 20; j++ {
+			wg.Add(1)
+			path := "dir" + string(rune('a'+i%26)) + "/file" + string(rune('a'+j%26))
+			go func(path string) {
+				defer wg.Done()
+				s.changed(path, fakeFileInfo{mtime: time.Now(), size: 1})
+			}(path)
+		}
+	}
+	wg.Wait()
+}
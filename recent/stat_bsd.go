@@ -0,0 +1,24 @@
+//go:build freebsd || netbsd
+
+package recent
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+//platformTimes extracts atime, ctime, and btime from fi's underlying
+//*syscall.Stat_t. FreeBSD and NetBSD both carry a birth time; OpenBSD
+//and DragonFly BSD, which name or omit these fields differently, are
+//not covered here.
+func platformTimes(fi os.FileInfo) (atime, ctime time.Time, ctimeOK bool, btime time.Time, btimeOK bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}, false, time.Time{}, false
+	}
+	atime = time.Unix(st.Atimespec.Sec, st.Atimespec.Nsec)
+	ctime = time.Unix(st.Ctimespec.Sec, st.Ctimespec.Nsec)
+	btime = time.Unix(st.Birthtimespec.Sec, st.Birthtimespec.Nsec)
+	return atime, ctime, true, btime, true
+}
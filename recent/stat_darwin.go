@@ -0,0 +1,22 @@
+//go:build darwin
+
+package recent
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+//platformTimes extracts atime, ctime, and btime from fi's underlying
+//*syscall.Stat_t; Darwin's stat(2) carries all three.
+func platformTimes(fi os.FileInfo) (atime, ctime time.Time, ctimeOK bool, btime time.Time, btimeOK bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}, false, time.Time{}, false
+	}
+	atime = time.Unix(st.Atimespec.Sec, st.Atimespec.Nsec)
+	ctime = time.Unix(st.Ctimespec.Sec, st.Ctimespec.Nsec)
+	btime = time.Unix(st.Birthtimespec.Sec, st.Birthtimespec.Nsec)
+	return atime, ctime, true, btime, true
+}
@@ -0,0 +1,23 @@
+//go:build linux
+
+package recent
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+//platformTimes extracts atime and ctime from fi's underlying *syscall.Stat_t.
+//Linux's classic stat(2) has no birth time, and reading it with statx(2)
+//needs either cgo or an architecture-specific syscall number, so btime
+//is reported as unavailable here rather than pulling in either.
+func platformTimes(fi os.FileInfo) (atime, ctime time.Time, ctimeOK bool, btime time.Time, btimeOK bool) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, time.Time{}, false, time.Time{}, false
+	}
+	atime = time.Unix(st.Atim.Sec, st.Atim.Nsec)
+	ctime = time.Unix(st.Ctim.Sec, st.Ctim.Nsec)
+	return atime, ctime, true, time.Time{}, false
+}
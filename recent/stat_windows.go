@@ -0,0 +1,22 @@
+//go:build windows
+
+package recent
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+//platformTimes extracts atime and btime from fi's underlying
+//*syscall.Win32FileAttributeData. Windows has no equivalent of Unix's
+//ctime, so ctimeOK is always false.
+func platformTimes(fi os.FileInfo) (atime, ctime time.Time, ctimeOK bool, btime time.Time, btimeOK bool) {
+	d, ok := fi.Sys().(*syscall.Win32FileAttributeData)
+	if !ok {
+		return time.Time{}, time.Time{}, false, time.Time{}, false
+	}
+	atime = time.Unix(0, d.LastAccessTime.Nanoseconds())
+	btime = time.Unix(0, d.CreationTime.Nanoseconds())
+	return atime, time.Time{}, false, btime, true
+}
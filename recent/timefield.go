@@ -0,0 +1,46 @@
+package recent
+
+import "fmt"
+
+//TimeField names a timestamp kept in a file's inode.
+type TimeField int
+
+//The timestamps recent(1) can compare against.
+const (
+	MTime TimeField = iota //last modified
+	ATime                  //last accessed
+	CTime                  //last changed (metadata or content)
+	BTime                  //created, if the platform and filesystem record it
+)
+
+func (f TimeField) String() string {
+	switch f {
+	case MTime:
+		return "mtime"
+	case ATime:
+		return "atime"
+	case CTime:
+		return "ctime"
+	case BTime:
+		return "btime"
+	default:
+		return "unknown"
+	}
+}
+
+//ParseTimeField parses one of "mtime", "atime", "ctime", or "btime" into
+//the corresponding TimeField.
+func ParseTimeField(s string) (TimeField, error) {
+	switch s {
+	case "mtime":
+		return MTime, nil
+	case "atime":
+		return ATime, nil
+	case "ctime":
+		return CTime, nil
+	case "btime":
+		return BTime, nil
+	default:
+		return 0, fmt.Errorf("recent: %q is not one of mtime, atime, ctime, btime", s)
+	}
+}
@@ -0,0 +1,79 @@
+package recent
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//TimeRange is a closed interval of modification times to match against.
+//A zero Since or Until means that end of the interval is unbounded.
+type TimeRange struct {
+	Since, Until time.Time
+}
+
+//Contains reports whether t falls within the closed interval [Since, Until].
+func (r TimeRange) Contains(t time.Time) bool {
+	if !r.Since.IsZero() && t.Before(r.Since) {
+		return false
+	}
+	if !r.Until.IsZero() && t.After(r.Until) {
+		return false
+	}
+	return true
+}
+
+//ParseTime parses the absolute or relative time in s, as accepted by the
+//-after, -before, and -on flags: RFC3339, "2006-01-02 15:04:05",
+//"2006-01-02" (date, reported via dateOnly), a 10/13/16 digit unix
+//timestamp (seconds/milliseconds/microseconds), or a duration string
+//such as "24h", taken as relative to now.
+func ParseTime(s string, now time.Time) (t time.Time, dateOnly bool, err error) {
+	if t, ok := parseUnixTime(s); ok {
+		return t, false, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("2006-01-02 15:04:05", s); err == nil {
+		return t, false, nil
+	}
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t, true, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(-d), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("recent: %q is not a recognized time, date, timestamp, or duration", s)
+}
+
+//parseUnixTime recognizes s as a unix timestamp: an all-digit string,
+//optionally negative, of 10, 13, or 16 digits, read as seconds,
+//milliseconds, or microseconds since the epoch respectively.
+func parseUnixTime(s string) (time.Time, bool) {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" {
+		return time.Time{}, false
+	}
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return time.Time{}, false
+		}
+	}
+
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch len(digits) {
+	case 10:
+		return time.Unix(n, 0), true
+	case 13:
+		return time.UnixMilli(n), true
+	case 16:
+		return time.UnixMicro(n), true
+	}
+	return time.Time{}, false
+}
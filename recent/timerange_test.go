@@ -0,0 +1,102 @@
+package recent
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseUnixTime(t *testing.T) {
+	cases := []struct {
+		s    string
+		want time.Time
+		ok   bool
+	}{
+		{"1700000000", time.Unix(1700000000, 0), true},
+		{"1700000000000", time.UnixMilli(1700000000000), true},
+		{"1700000000000000", time.UnixMicro(1700000000000000), true},
+		{"-1700000000", time.Unix(-1700000000, 0), true},
+		{"170000000", time.Time{}, false},   //9 digits: not a recognized length
+		{"17000000000", time.Time{}, false}, //11 digits: not a recognized length
+		{"2024-01-01", time.Time{}, false},
+		{"", time.Time{}, false},
+	}
+	for _, c := range cases {
+		got, ok := parseUnixTime(c.s)
+		if ok != c.ok {
+			t.Errorf("parseUnixTime(%q) ok = %v, want %v", c.s, ok, c.ok)
+			continue
+		}
+		if ok && !got.Equal(c.want) {
+			t.Errorf("parseUnixTime(%q) = %v, want %v", c.s, got, c.want)
+		}
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	got, dateOnly, err := ParseTime("2024-01-02T15:04:05Z", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dateOnly {
+		t.Error("RFC3339 input should not be reported as dateOnly")
+	}
+	want := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	got, dateOnly, err = ParseTime("2024-01-02", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !dateOnly {
+		t.Error("a bare date should be reported as dateOnly")
+	}
+	if got.Year() != 2024 || got.Month() != 1 || got.Day() != 2 {
+		t.Errorf("got %v, want 2024-01-02", got)
+	}
+
+	got, _, err = ParseTime("24h", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(now.Add(-24 * time.Hour)) {
+		t.Errorf("duration should be relative to now: got %v, want %v", got, now.Add(-24*time.Hour))
+	}
+
+	got, _, err = ParseTime("1700000000", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("got %v, want unix timestamp", got)
+	}
+
+	if _, _, err := ParseTime("not a time", now); err == nil {
+		t.Error("expected an error for unrecognized input")
+	}
+}
+
+func TestTimeRangeContains(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name string
+		r    TimeRange
+		t    time.Time
+		want bool
+	}{
+		{"unbounded", TimeRange{}, base, true},
+		{"before since", TimeRange{Since: base}, base.Add(-time.Second), false},
+		{"at since", TimeRange{Since: base}, base, true},
+		{"after until", TimeRange{Until: base}, base.Add(time.Second), false},
+		{"at until", TimeRange{Until: base}, base, true},
+		{"within range", TimeRange{Since: base, Until: base.Add(time.Hour)}, base.Add(time.Minute), true},
+	}
+	for _, c := range cases {
+		if got := c.r.Contains(c.t); got != c.want {
+			t.Errorf("%s: Contains(%v) = %v, want %v", c.name, c.t, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+	"time"
+)
+
+//FileRecord is a single match in the form every Sink consumes: a text
+//template or encoder never needs to know about recent.Result or os.FileInfo.
+//A record for a file reported removed since a snapshot has every field
+//but Path zeroed.
+type FileRecord struct {
+	Path  string
+	MTime time.Time
+	Size  int64
+	Mode  os.FileMode
+	IsDir bool
+
+	//Removed is set instead of the fields above when a path was reported
+	//removed since a snapshot: every field but Path and Removed is zero.
+	Removed bool
+}
+
+//Sink is where matches go once they've been found, sorted, and limited.
+//Emit is called once per match; Close is called once after the last Emit
+//to let a Sink flush or release anything it's holding open.
+type Sink interface {
+	Emit(FileRecord) error
+	Close() error
+}
+
+//jsonRecord is the wire shape of a FileRecord: mtime as RFC3339 and mode
+//as its string form, rather than Go's zero-value defaults for either.
+type jsonRecord struct {
+	Path    string `json:"path"`
+	MTime   string `json:"mtime"`
+	Size    int64  `json:"size"`
+	Mode    string `json:"mode"`
+	IsDir   bool   `json:"is_dir"`
+	Removed bool   `json:"removed,omitempty"`
+}
+
+//jsonSink writes one JSON object per line, for piping into jq or similar.
+type jsonSink struct {
+	enc *json.Encoder
+}
+
+func newJSONSink(w io.Writer) *jsonSink {
+	return &jsonSink{enc: json.NewEncoder(w)}
+}
+
+func (s *jsonSink) Emit(r FileRecord) error {
+	return s.enc.Encode(jsonRecord{
+		Path:    r.Path,
+		MTime:   r.MTime.Format(time.RFC3339),
+		Size:    r.Size,
+		Mode:    r.Mode.String(),
+		IsDir:   r.IsDir,
+		Removed: r.Removed,
+	})
+}
+
+func (s *jsonSink) Close() error { return nil }
+
+//tsvSink writes mtime, size, and path as tab-separated columns, the same
+//fields -l prints, for scripts that want fixed columns without parsing
+//JSON.
+type tsvSink struct {
+	w          io.Writer
+	TimeFormat string
+	Now        time.Time
+}
+
+func (s *tsvSink) Emit(r FileRecord) error {
+	if r.Removed {
+		_, err := fmt.Fprintf(s.w, "-\t-\t%s\n", r.Path)
+		return err
+	}
+	_, err := fmt.Fprintf(s.w, "%s\t%d\t%s\n", formatTime(r.MTime, s.TimeFormat, s.Now), r.Size, r.Path)
+	return err
+}
+
+func (s *tsvSink) Close() error { return nil }
+
+//formatSink renders each FileRecord through a user-supplied text/template.
+type formatSink struct {
+	tmpl *template.Template
+	w    io.Writer
+}
+
+func (s *formatSink) Emit(r FileRecord) error {
+	if err := s.tmpl.Execute(s.w, r); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(s.w)
+	return err
+}
+
+func (s *formatSink) Close() error { return nil }
+
+//plainSink is the default text output: one path per record, in long
+//format with mtime and size columns if Long is set.
+type plainSink struct {
+	w          io.Writer
+	sep        string
+	Long       bool
+	TimeFormat string
+	Now        time.Time
+}
+
+func (s *plainSink) Emit(r FileRecord) error {
+	if !s.Long {
+		_, err := fmt.Fprint(s.w, r.Path, s.sep)
+		return err
+	}
+	if r.Removed {
+		_, err := fmt.Fprintf(s.w, "-\t-\t%s%s", r.Path, s.sep)
+		return err
+	}
+	_, err := fmt.Fprintf(s.w, "%s\t%d\t%s%s", formatTime(r.MTime, s.TimeFormat, s.Now), r.Size, r.Path, s.sep)
+	return err
+}
+
+func (s *plainSink) Close() error { return nil }
+
+//quietSink discards every record, only recording whether it ever saw one,
+//for -q.
+type quietSink struct {
+	Matched bool
+}
+
+func (s *quietSink) Emit(FileRecord) error {
+	s.Matched = true
+	return nil
+}
+
+func (s *quietSink) Close() error { return nil }
+
+//formatTime renders t according to format: "iso" (the default) or
+//"rfc3339" for fixed layouts, "relative" for "3h ago"-style output, or
+//any other value as a Go time layout.
+func formatTime(t time.Time, format string, now time.Time) string {
+	switch format {
+	case "", "iso":
+		return t.Format("2006-01-02 15:04:05")
+	case "rfc3339":
+		return t.Format(time.RFC3339)
+	case "relative":
+		return relativeTime(t, now)
+	default:
+		return t.Format(format)
+	}
+}
+
+//relativeTime renders the gap between t and now as "Nh ago"-style text.
+func relativeTime(t, now time.Time) string {
+	d := now.Sub(t)
+	suffix := "ago"
+	if d < 0 {
+		d = -d
+		suffix = "from now"
+	}
+
+	var n time.Duration
+	var unit string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		n, unit = d/time.Minute, "m"
+	case d < 24*time.Hour:
+		n, unit = d/time.Hour, "h"
+	default:
+		n, unit = d/(24*time.Hour), "d"
+	}
+	return fmt.Sprintf("%d%s %s", n, unit, suffix)
+}